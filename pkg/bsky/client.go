@@ -0,0 +1,247 @@
+// Package bsky wraps the generated indigo xrpc client with the session
+// and retry handling every caller in this repo ends up needing: logging
+// in, refreshing an expired JWT, and backing off on rate limits.
+package bsky
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/bluesky-social/indigo/api/atproto"
+	indigobsky "github.com/bluesky-social/indigo/api/bsky"
+	"github.com/bluesky-social/indigo/util"
+	"github.com/bluesky-social/indigo/xrpc"
+)
+
+// Profile and ProfileDetailed re-export the indigo view types callers need,
+// so packages using this client don't also have to import indigo/api/bsky.
+type (
+	Profile         = indigobsky.ActorDefs_ProfileView
+	ProfileDetailed = indigobsky.ActorDefs_ProfileViewDetailed
+)
+
+// Client is an authenticated Bluesky client that transparently refreshes
+// its session on auth errors and backs off on rate limits.
+type Client struct {
+	xrpc   *xrpc.Client
+	handle string
+	appPwd string
+}
+
+// Option customizes a Client constructed by NewClient.
+type Option func(*Client)
+
+// WithHTTPClient overrides the underlying *http.Client, e.g. to point a
+// test at an httptest.Server instead of a real instance.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.xrpc.Client = hc }
+}
+
+// NewClient authenticates handle/appPwd against instance and returns a
+// ready-to-use Client.
+func NewClient(ctx context.Context, instance, handle, appPwd string, opts ...Option) (*Client, error) {
+	c := &Client{
+		xrpc: &xrpc.Client{
+			Client: rateLimitedHTTPClient(),
+			Host:   instance,
+			Auth:   &xrpc.AuthInfo{Handle: handle},
+		},
+		handle: handle,
+		appPwd: appPwd,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if err := c.login(ctx); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// DID returns the DID of the authenticated account.
+func (c *Client) DID() string { return c.xrpc.Auth.Did }
+
+func (c *Client) login(ctx context.Context) error {
+	auth, err := atproto.ServerCreateSession(ctx, c.xrpc, &atproto.ServerCreateSession_Input{
+		Identifier: c.handle,
+		Password:   c.appPwd,
+	})
+	if err != nil {
+		return fmt.Errorf("ServerCreateSession failed with %w", err)
+	}
+	c.xrpc.Auth.AccessJwt = auth.AccessJwt
+	c.xrpc.Auth.RefreshJwt = auth.RefreshJwt
+	c.xrpc.Auth.Did = auth.Did
+	c.xrpc.Auth.Handle = auth.Handle
+	return nil
+}
+
+func (c *Client) refresh(ctx context.Context) error {
+	refreshAuth := *c.xrpc
+	refreshAuth.Auth = &xrpc.AuthInfo{
+		AccessJwt:  c.xrpc.Auth.RefreshJwt,
+		RefreshJwt: c.xrpc.Auth.RefreshJwt,
+		Did:        c.xrpc.Auth.Did,
+		Handle:     c.xrpc.Auth.Handle,
+	}
+	sess, err := atproto.ServerRefreshSession(ctx, &refreshAuth)
+	if err != nil {
+		return fmt.Errorf("ServerRefreshSession failed with %w", err)
+	}
+	c.xrpc.Auth.AccessJwt = sess.AccessJwt
+	c.xrpc.Auth.RefreshJwt = sess.RefreshJwt
+	return nil
+}
+
+// withAuthRetry runs fn once, and if it fails with an expired/invalid
+// token error, refreshes the session and retries fn exactly once more.
+func (c *Client) withAuthRetry(ctx context.Context, fn func() error) error {
+	err := fn()
+	if err != nil && isAuthError(err) {
+		if rerr := c.refresh(ctx); rerr != nil {
+			return fmt.Errorf("refreshing session after auth error (%v): %w", err, rerr)
+		}
+		err = fn()
+	}
+	return err
+}
+
+func isAuthError(err error) bool {
+	var xerr *xrpc.Error
+	if errors.As(err, &xerr) && xerr.StatusCode == http.StatusUnauthorized {
+		return true
+	}
+	return strings.Contains(err.Error(), "ExpiredToken") || strings.Contains(err.Error(), "InvalidToken")
+}
+
+// Profile fetches the profile of the given DID.
+func (c *Client) Profile(ctx context.Context, did string) (*ProfileDetailed, error) {
+	var out *ProfileDetailed
+	err := c.withAuthRetry(ctx, func() error {
+		acc, err := indigobsky.ActorGetProfile(ctx, c.xrpc, did)
+		if err != nil {
+			return err
+		}
+		out = acc
+		return nil
+	})
+	return out, err
+}
+
+// Following returns every account did follows.
+func (c *Client) Following(ctx context.Context, did string, pageSize int64) ([]*Profile, error) {
+	var accounts []*Profile
+	var cursor string
+	for {
+		var page *indigobsky.GraphGetFollows_Output
+		err := c.withAuthRetry(ctx, func() error {
+			var err error
+			page, err = indigobsky.GraphGetFollows(ctx, c.xrpc, did, cursor, pageSize)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, page.Follows...)
+		if page.Cursor != nil {
+			cursor = *page.Cursor
+		}
+		if len(page.Follows) == 0 {
+			break
+		}
+	}
+	return accounts, nil
+}
+
+// Followers returns every account that follows did.
+func (c *Client) Followers(ctx context.Context, did string, pageSize int64) ([]*Profile, error) {
+	var accounts []*Profile
+	var cursor string
+	for {
+		var page *indigobsky.GraphGetFollowers_Output
+		err := c.withAuthRetry(ctx, func() error {
+			var err error
+			page, err = indigobsky.GraphGetFollowers(ctx, c.xrpc, did, cursor, pageSize)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, page.Followers...)
+		if page.Cursor != nil {
+			cursor = *page.Cursor
+		}
+		if len(page.Followers) == 0 {
+			break
+		}
+	}
+	return accounts, nil
+}
+
+// Mutes returns every account the authenticated account has muted.
+// app.bsky.graph.getMutes takes no actor, so did is not sent.
+func (c *Client) Mutes(ctx context.Context, did string, pageSize int64) ([]*Profile, error) {
+	var accounts []*Profile
+	var cursor string
+	for {
+		var page *indigobsky.GraphGetMutes_Output
+		err := c.withAuthRetry(ctx, func() error {
+			var err error
+			page, err = indigobsky.GraphGetMutes(ctx, c.xrpc, cursor, pageSize)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, page.Mutes...)
+		if page.Cursor != nil {
+			cursor = *page.Cursor
+		}
+		if len(page.Mutes) == 0 {
+			break
+		}
+	}
+	return accounts, nil
+}
+
+// Blocks returns every account the authenticated account has blocked.
+// app.bsky.graph.getBlocks takes no actor, so did is not sent.
+func (c *Client) Blocks(ctx context.Context, did string, pageSize int64) ([]*Profile, error) {
+	var accounts []*Profile
+	var cursor string
+	for {
+		var page *indigobsky.GraphGetBlocks_Output
+		err := c.withAuthRetry(ctx, func() error {
+			var err error
+			page, err = indigobsky.GraphGetBlocks(ctx, c.xrpc, cursor, pageSize)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, page.Blocks...)
+		if page.Cursor != nil {
+			cursor = *page.Cursor
+		}
+		if len(page.Blocks) == 0 {
+			break
+		}
+	}
+	return accounts, nil
+}
+
+// rateLimitedHTTPClient wraps util.RobustHTTPClient with a transport that
+// honors 429 responses' Retry-After header instead of surfacing them as
+// plain request failures.
+func rateLimitedHTTPClient() *http.Client {
+	hc := util.RobustHTTPClient()
+	base := hc.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	hc.Transport = &instrumentedTransport{next: &rateLimitedTransport{next: base}}
+	return hc
+}
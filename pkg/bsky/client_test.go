@@ -0,0 +1,104 @@
+package bsky
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_RefreshesSessionOnExpiredToken(t *testing.T) {
+	var profileCalls int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/xrpc/com.atproto.server.createSession":
+			json.NewEncoder(w).Encode(map[string]string{
+				"accessJwt":  "initial-token",
+				"refreshJwt": "refresh-token",
+				"did":        "did:plc:self",
+				"handle":     "self.test",
+			})
+		case "/xrpc/com.atproto.server.refreshSession":
+			json.NewEncoder(w).Encode(map[string]string{
+				"accessJwt":  "fresh-token",
+				"refreshJwt": "refresh-token-2",
+				"did":        "did:plc:self",
+				"handle":     "self.test",
+			})
+		case "/xrpc/app.bsky.actor.getProfile":
+			if atomic.AddInt32(&profileCalls, 1) == 1 {
+				w.WriteHeader(http.StatusUnauthorized)
+				json.NewEncoder(w).Encode(map[string]string{"error": "ExpiredToken", "message": "token has expired"})
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]string{"did": "did:plc:target", "handle": "target.test"})
+		default:
+			t.Fatalf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	c, err := NewClient(ctx, srv.URL, "self.test", "app-pwd", WithHTTPClient(srv.Client()))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	profile, err := c.Profile(ctx, "did:plc:target")
+	if err != nil {
+		t.Fatalf("Profile: %v", err)
+	}
+	if profile.Did != "did:plc:target" {
+		t.Errorf("Profile.Did = %q, want %q", profile.Did, "did:plc:target")
+	}
+	if got := atomic.LoadInt32(&profileCalls); got != 2 {
+		t.Errorf("getProfile called %d times, want 2 (initial 401 + retry after refresh)", got)
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestRateLimitedTransport_RetriesOn429AndPreservesBody(t *testing.T) {
+	var calls int
+	next := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		body, _ := io.ReadAll(req.Body)
+		if calls == 1 {
+			return &http.Response{
+				StatusCode: http.StatusTooManyRequests,
+				Header:     http.Header{"Retry-After": []string{"0"}},
+				Body:       io.NopCloser(strings.NewReader("")),
+			}, nil
+		}
+		if string(body) != "hello" {
+			t.Errorf("retried request body = %q, want %q (req.Body wasn't rewound via GetBody)", body, "hello")
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("ok"))}, nil
+	})
+
+	transport := &rateLimitedTransport{next: next, baseDelay: time.Millisecond}
+	req, err := http.NewRequest(http.MethodPost, "http://example.test/", strings.NewReader("hello"))
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("final status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if calls != 2 {
+		t.Errorf("RoundTrip called next %d times, want 2 (one 429, one success)", calls)
+	}
+}
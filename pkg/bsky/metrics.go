@@ -0,0 +1,43 @@
+package bsky
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	apiCallsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "bsky_audit_api_calls_total",
+		Help: "Total XRPC calls made to the Bluesky instance, by endpoint and response status.",
+	}, []string{"endpoint", "status"})
+
+	apiCallDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "bsky_audit_api_call_duration_seconds",
+		Help: "Latency of XRPC calls made to the Bluesky instance, by endpoint.",
+	}, []string{"endpoint"})
+)
+
+// instrumentedTransport hands every request to next (typically a
+// rateLimitedTransport) and records apiCallsTotal and apiCallDuration once
+// next returns.
+type instrumentedTransport struct {
+	next http.RoundTripper
+}
+
+func (t *instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	endpoint := req.URL.Path
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	apiCallDuration.WithLabelValues(endpoint).Observe(time.Since(start).Seconds())
+
+	status := "error"
+	if err == nil {
+		status = strconv.Itoa(resp.StatusCode)
+	}
+	apiCallsTotal.WithLabelValues(endpoint, status).Inc()
+	return resp, err
+}
@@ -0,0 +1,61 @@
+package bsky
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// rateLimitedTransport retries a request with exponential backoff when the
+// server responds 429, honoring Retry-After when present.
+type rateLimitedTransport struct {
+	next       http.RoundTripper
+	maxRetries int
+	baseDelay  time.Duration
+}
+
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	maxRetries := t.maxRetries
+	if maxRetries == 0 {
+		maxRetries = 5
+	}
+	delay := t.baseDelay
+	if delay == 0 {
+		delay = 500 * time.Millisecond
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := t.next.RoundTrip(req)
+		if err != nil || resp.StatusCode != http.StatusTooManyRequests || attempt >= maxRetries {
+			return resp, err
+		}
+
+		wait := delay
+		if ra := resp.Header.Get("Retry-After"); ra != "" {
+			if secs, perr := strconv.Atoi(ra); perr == nil {
+				wait = time.Duration(secs) * time.Second
+			}
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(wait):
+		}
+		delay *= 2
+
+		// req.Body was already consumed by the attempt above; rewind it
+		// via GetBody so POSTs (e.g. ServerCreateSession, ServerRefreshSession)
+		// are retried with their original payload instead of an empty body.
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Periodically poll follows/followers/mutes/blocks and emit an audit log of changes",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := buildClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		sink, err := newSink(viper.GetString("daemon.sink"), viper.GetString("daemon.data-dir"))
+		if err != nil {
+			return fmt.Errorf("newSink failed with %w", err)
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+		defer stop()
+
+		if listen := viper.GetString("daemon.listen"); listen != "" {
+			if err := serveMetrics(ctx, listen); err != nil {
+				return err
+			}
+		}
+
+		return runDaemon(ctx, client, client.DID(), viper.GetString("daemon.data-dir"), viper.GetDuration("daemon.interval"), viper.GetInt64("page-size"), sink)
+	},
+}
+
+func init() {
+	daemonCmd.Flags().Duration("interval", 5*time.Minute, "how often to re-poll follows/followers/mutes/blocks")
+	daemonCmd.Flags().String("data-dir", "./bsky-audit-data", "directory used to persist snapshots and file-based sink output")
+	daemonCmd.Flags().String("sink", "stdout", "audit log sink: stdout, jsonl, or sqlite")
+	daemonCmd.Flags().String("listen", "", "address to serve /metrics and /healthz on, e.g. :9100 (disabled if empty)")
+
+	for _, name := range []string{"interval", "data-dir", "sink", "listen"} {
+		if err := viper.BindPFlag("daemon."+name, daemonCmd.Flags().Lookup(name)); err != nil {
+			panic(err)
+		}
+	}
+
+	rootCmd.AddCommand(daemonCmd)
+}
@@ -0,0 +1,19 @@
+package main
+
+import "github.com/spf13/cobra"
+
+var followersCmd = &cobra.Command{
+	Use:   "followers",
+	Short: "List accounts that follow the authenticated account",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := buildClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+		return printAccounts(cmd.Context(), client.DID(), client.Followers)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(followersCmd)
+}
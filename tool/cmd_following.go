@@ -0,0 +1,19 @@
+package main
+
+import "github.com/spf13/cobra"
+
+var followingCmd = &cobra.Command{
+	Use:   "following",
+	Short: "List accounts the authenticated account follows",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := buildClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+		return printAccounts(cmd.Context(), client.DID(), client.Following)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(followingCmd)
+}
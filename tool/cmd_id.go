@@ -0,0 +1,24 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var idCmd = &cobra.Command{
+	Use:   "id",
+	Short: "Print the DID of the authenticated account",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := buildClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+		fmt.Print(client.DID())
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(idCmd)
+}
@@ -0,0 +1,19 @@
+package main
+
+import "github.com/spf13/cobra"
+
+var mutesCmd = &cobra.Command{
+	Use:   "mutes",
+	Short: "List accounts muted by the authenticated account",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := buildClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+		return printAccounts(cmd.Context(), client.DID(), client.Mutes)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(mutesCmd)
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var streamCmd = &cobra.Command{
+	Use:   "stream",
+	Short: "Subscribe to a Jetstream firehose for real-time follow/block audit events",
+	Long: `stream complements the daemon command: instead of polling on an
+interval, it watches a Jetstream websocket for app.bsky.graph.follow,
+app.bsky.graph.block, and app.bsky.graph.listitem commits that involve the
+authenticated account (its own follows and blocks, and other accounts
+following, unfollowing, or blocking it), and emits audit events as they
+happen rather than once per --interval.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := buildClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		sink, err := newSink(viper.GetString("stream.sink"), viper.GetString("stream.data-dir"))
+		if err != nil {
+			return fmt.Errorf("newSink failed with %w", err)
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+		defer stop()
+
+		return runStream(ctx, client, client.DID(), viper.GetString("stream.data-dir"), viper.GetString("stream.jetstream-url"), viper.GetInt64("page-size"), sink)
+	},
+}
+
+func init() {
+	streamCmd.Flags().String("data-dir", "./bsky-audit-data", "directory used to persist the bootstrap snapshot and file-based sink output")
+	streamCmd.Flags().String("sink", "stdout", "audit log sink: stdout, jsonl, or sqlite")
+	streamCmd.Flags().String("jetstream-url", defaultJetstreamURL, "Jetstream websocket URL to subscribe to")
+
+	for _, name := range []string{"data-dir", "sink", "jetstream-url"} {
+		if err := viper.BindPFlag("stream."+name, streamCmd.Flags().Lookup(name)); err != nil {
+			panic(err)
+		}
+	}
+
+	rootCmd.AddCommand(streamCmd)
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/tehcyx/bsky-audit-log/pkg/bsky"
+)
+
+// AuditEvent is a single observed change between two snapshots, e.g. a
+// follow gained or a block lifted.
+type AuditEvent struct {
+	Time   time.Time `json:"time"`
+	Action string    `json:"action"`
+	Did    string    `json:"did"`
+	Handle string    `json:"handle"`
+}
+
+func (e AuditEvent) String() string {
+	return fmt.Sprintf("%s %s %s %s", e.Time.UTC().Format(time.RFC3339), e.Did, e.Action, e.Handle)
+}
+
+// Sink persists audit events as they are produced by the daemon.
+type Sink interface {
+	Write(ctx context.Context, evt AuditEvent) error
+	Close() error
+}
+
+// snapshot is the set of relationships observed at a point in time, keyed
+// by DID so two snapshots can be diffed cheaply.
+type snapshot struct {
+	Following map[string]*bsky.Profile `json:"following"`
+	Followers map[string]*bsky.Profile `json:"followers"`
+	Mutes     map[string]*bsky.Profile `json:"mutes"`
+	Blocks    map[string]*bsky.Profile `json:"blocks"`
+}
+
+func toSet(accounts []*bsky.Profile) map[string]*bsky.Profile {
+	set := make(map[string]*bsky.Profile, len(accounts))
+	for _, acc := range accounts {
+		set[acc.Did] = acc
+	}
+	return set
+}
+
+func takeSnapshot(ctx context.Context, c *bsky.Client, did string, pageSize int64) (*snapshot, error) {
+	fs, err := c.Following(ctx, did, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("following: %w", err)
+	}
+	frs, err := c.Followers(ctx, did, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("followers: %w", err)
+	}
+	ms, err := c.Mutes(ctx, did, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("muted: %w", err)
+	}
+	bs, err := c.Blocks(ctx, did, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("blocked: %w", err)
+	}
+	return &snapshot{
+		Following: toSet(fs),
+		Followers: toSet(frs),
+		Mutes:     toSet(ms),
+		Blocks:    toSet(bs),
+	}, nil
+}
+
+// diff compares two maps keyed by DID and reports additions/removals as
+// audit events using the given verb pair.
+func diff(now time.Time, prev, cur map[string]*bsky.Profile, gained, lost string) []AuditEvent {
+	var events []AuditEvent
+	for did, acc := range cur {
+		if _, ok := prev[did]; !ok {
+			events = append(events, AuditEvent{Time: now, Action: gained, Did: did, Handle: acc.Handle})
+		}
+	}
+	for did, acc := range prev {
+		if _, ok := cur[did]; !ok {
+			events = append(events, AuditEvent{Time: now, Action: lost, Did: did, Handle: acc.Handle})
+		}
+	}
+	return events
+}
+
+func diffSnapshots(now time.Time, prev, cur *snapshot) []AuditEvent {
+	var events []AuditEvent
+	events = append(events, diff(now, prev.Following, cur.Following, "followed", "unfollowed")...)
+	events = append(events, diff(now, prev.Followers, cur.Followers, "gained_follower", "lost_follower")...)
+	events = append(events, diff(now, prev.Mutes, cur.Mutes, "muted", "unmuted")...)
+	events = append(events, diff(now, prev.Blocks, cur.Blocks, "blocked", "unblocked")...)
+	return events
+}
+
+// loadState reads a previously persisted snapshot from dataDir, if any.
+// A missing file just means this is the first run.
+func loadState(dataDir string) (*snapshot, error) {
+	b, err := os.ReadFile(filepath.Join(dataDir, "state.json"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var s snapshot
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func saveState(dataDir string, s *snapshot) error {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dataDir, "state.json"), b, 0o644)
+}
+
+// runDaemon polls the four relationship endpoints on the given interval,
+// diffs each snapshot against the last one seen, and writes the resulting
+// audit events to sink. It returns when ctx is cancelled, e.g. on SIGINT.
+func runDaemon(ctx context.Context, c *bsky.Client, did, dataDir string, interval time.Duration, pageSize int64, sink Sink) error {
+	if err := os.MkdirAll(dataDir, 0o755); err != nil {
+		return fmt.Errorf("creating data dir: %w", err)
+	}
+
+	prev, err := loadState(dataDir)
+	if err != nil {
+		return fmt.Errorf("loading prior state: %w", err)
+	}
+
+	tick := func() error {
+		cur, err := takeSnapshot(ctx, c, did, pageSize)
+		if err != nil {
+			return err
+		}
+		if prev != nil {
+			for _, evt := range diffSnapshots(time.Now(), prev, cur) {
+				recordAuditEvent(evt)
+				if err := sink.Write(ctx, evt); err != nil {
+					return fmt.Errorf("writing audit event: %w", err)
+				}
+			}
+		}
+		if err := saveState(dataDir, cur); err != nil {
+			return fmt.Errorf("saving state: %w", err)
+		}
+		prev = cur
+		return nil
+	}
+
+	if err := tick(); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return sink.Close()
+		case <-ticker.C:
+			if err := tick(); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/tehcyx/bsky-audit-log/pkg/bsky"
+)
+
+// Encoder writes accounts to an output stream in a particular format.
+// Callers must call Close once they're done encoding, to flush any
+// buffered state (e.g. the closing bracket of a JSON array).
+type Encoder interface {
+	Encode(acc *bsky.Profile) error
+	Close() error
+}
+
+// newEncoder returns the Encoder for the given --format name.
+func newEncoder(format string, w io.Writer) (Encoder, error) {
+	switch format {
+	case "", "csv":
+		return newCSVEncoder(w), nil
+	case "json":
+		return newJSONArrayEncoder(w), nil
+	case "ndjson":
+		return newNDJSONEncoder(w), nil
+	case "parquet":
+		return newParquetEncoder(w)
+	default:
+		return nil, fmt.Errorf("unknown --format %q", format)
+	}
+}
+
+func derefStr(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func derefBool(b *bool) bool {
+	return b != nil && *b
+}
+
+// csvEncoder writes one header row followed by one row per account,
+// covering every ActorDefs_ProfileView field callers have asked for by
+// name in the past (display name, description, indexed-at, viewer state).
+type csvEncoder struct {
+	w           *csv.Writer
+	wroteHeader bool
+}
+
+func newCSVEncoder(w io.Writer) *csvEncoder {
+	return &csvEncoder{w: csv.NewWriter(w)}
+}
+
+var csvHeader = []string{"did", "handle", "display_name", "description", "indexed_at", "following", "followed_by", "muted", "blocked_by"}
+
+func (e *csvEncoder) Encode(acc *bsky.Profile) error {
+	if !e.wroteHeader {
+		if err := e.w.Write(csvHeader); err != nil {
+			return err
+		}
+		e.wroteHeader = true
+	}
+
+	var following, followedBy string
+	var muted, blockedBy bool
+	if acc.Viewer != nil {
+		following = derefStr(acc.Viewer.Following)
+		followedBy = derefStr(acc.Viewer.FollowedBy)
+		muted = derefBool(acc.Viewer.Muted)
+		blockedBy = derefBool(acc.Viewer.BlockedBy)
+	}
+
+	return e.w.Write([]string{
+		acc.Did,
+		acc.Handle,
+		derefStr(acc.DisplayName),
+		derefStr(acc.Description),
+		derefStr(acc.IndexedAt),
+		following,
+		followedBy,
+		fmt.Sprintf("%t", muted),
+		fmt.Sprintf("%t", blockedBy),
+	})
+}
+
+func (e *csvEncoder) Close() error {
+	if !e.wroteHeader {
+		if err := e.w.Write(csvHeader); err != nil {
+			return err
+		}
+		e.wroteHeader = true
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// ndjsonEncoder writes one JSON object per line, the canonical format for
+// feeding jq/DuckDB/BigQuery.
+type ndjsonEncoder struct {
+	enc *json.Encoder
+}
+
+func newNDJSONEncoder(w io.Writer) *ndjsonEncoder {
+	return &ndjsonEncoder{enc: json.NewEncoder(w)}
+}
+
+func (e *ndjsonEncoder) Encode(acc *bsky.Profile) error { return e.enc.Encode(acc) }
+func (e *ndjsonEncoder) Close() error                   { return nil }
+
+// jsonArrayEncoder buffers every account and writes a single pretty-printed
+// JSON array on Close.
+type jsonArrayEncoder struct {
+	w    io.Writer
+	accs []*bsky.Profile
+}
+
+func newJSONArrayEncoder(w io.Writer) *jsonArrayEncoder {
+	return &jsonArrayEncoder{w: w}
+}
+
+func (e *jsonArrayEncoder) Encode(acc *bsky.Profile) error {
+	e.accs = append(e.accs, acc)
+	return nil
+}
+
+func (e *jsonArrayEncoder) Close() error {
+	if e.accs == nil {
+		e.accs = []*bsky.Profile{}
+	}
+	b, err := json.MarshalIndent(e.accs, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = e.w.Write(append(b, '\n'))
+	return err
+}
@@ -0,0 +1,14 @@
+//go:build !parquet
+
+package main
+
+import (
+	"fmt"
+	"io"
+)
+
+// newParquetEncoder is stubbed out unless this binary is built with
+// -tags parquet, so the default build doesn't pull in parquet-go.
+func newParquetEncoder(io.Writer) (Encoder, error) {
+	return nil, fmt.Errorf("--format=parquet requires a binary built with -tags parquet")
+}
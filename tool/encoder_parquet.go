@@ -0,0 +1,55 @@
+//go:build parquet
+
+package main
+
+import (
+	"io"
+
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/tehcyx/bsky-audit-log/pkg/bsky"
+)
+
+// parquetRow is the flattened shape written to the parquet file; parquet-go
+// derives the schema from these struct tags.
+type parquetRow struct {
+	Did         string `parquet:"did"`
+	Handle      string `parquet:"handle"`
+	DisplayName string `parquet:"display_name,optional"`
+	Description string `parquet:"description,optional"`
+	IndexedAt   string `parquet:"indexed_at,optional"`
+	Following   string `parquet:"following,optional"`
+	FollowedBy  string `parquet:"followed_by,optional"`
+	Muted       bool   `parquet:"muted"`
+	BlockedBy   bool   `parquet:"blocked_by"`
+}
+
+type parquetEncoder struct {
+	w *parquet.GenericWriter[parquetRow]
+}
+
+func newParquetEncoder(w io.Writer) (*parquetEncoder, error) {
+	return &parquetEncoder{w: parquet.NewGenericWriter[parquetRow](w)}, nil
+}
+
+func (e *parquetEncoder) Encode(acc *bsky.Profile) error {
+	row := parquetRow{
+		Did:         acc.Did,
+		Handle:      acc.Handle,
+		DisplayName: derefStr(acc.DisplayName),
+		Description: derefStr(acc.Description),
+		IndexedAt:   derefStr(acc.IndexedAt),
+	}
+	if acc.Viewer != nil {
+		row.Following = derefStr(acc.Viewer.Following)
+		row.FollowedBy = derefStr(acc.Viewer.FollowedBy)
+		row.Muted = derefBool(acc.Viewer.Muted)
+		row.BlockedBy = derefBool(acc.Viewer.BlockedBy)
+	}
+	_, err := e.w.Write([]parquetRow{row})
+	return err
+}
+
+func (e *parquetEncoder) Close() error {
+	return e.w.Close()
+}
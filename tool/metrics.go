@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	followsGainedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "bsky_audit_follows_gained_total",
+		Help: "Total number of new follows observed by the daemon.",
+	})
+	followsLostTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "bsky_audit_follows_lost_total",
+		Help: "Total number of unfollows observed by the daemon.",
+	})
+	blocksAddedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "bsky_audit_blocks_added_total",
+		Help: "Total number of new blocks observed by the daemon.",
+	})
+	blocksRemovedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "bsky_audit_blocks_removed_total",
+		Help: "Total number of lifted blocks observed by the daemon.",
+	})
+)
+
+// recordAuditEvent updates the metric matching evt's action, if any.
+func recordAuditEvent(evt AuditEvent) {
+	switch evt.Action {
+	case "followed":
+		followsGainedTotal.Inc()
+	case "unfollowed":
+		followsLostTotal.Inc()
+	case "blocked":
+		blocksAddedTotal.Inc()
+	case "unblocked":
+		blocksRemovedTotal.Inc()
+	}
+}
+
+// serveMetrics starts a /metrics and /healthz HTTP server on listen, and
+// shuts it down when ctx is cancelled. It binds the listener before
+// returning, so a bad --listen address (e.g. a port already in use) is
+// reported synchronously instead of failing silently in the background.
+func serveMetrics(ctx context.Context, listen string) error {
+	ln, err := net.Listen("tcp", listen)
+	if err != nil {
+		return fmt.Errorf("binding metrics listener on %q: %w", listen, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, "ok")
+	})
+
+	srv := &http.Server{Handler: mux}
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Fprintln(os.Stderr, "metrics server failed:", err)
+		}
+	}()
+
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/tehcyx/bsky-audit-log/pkg/bsky"
+)
+
+var cfgFile string
+
+var rootCmd = &cobra.Command{
+	Use:   "bsky-audit-log",
+	Short: "Inspect and audit a Bluesky account's follow graph",
+	Long: `bsky-audit-log logs into a Bluesky (AT Protocol) account and reports
+on its follows, followers, mutes, and blocks, and can watch them over
+time for an audit trail of who followed, unfollowed, blocked, or
+unblocked whom.`,
+}
+
+// Execute runs the command tree, printing any error to stderr and
+// exiting non-zero rather than panicking.
+func Execute() {
+	if err := rootCmd.Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func init() {
+	cobra.OnInitialize(initConfig)
+
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default $HOME/.bsky-audit-log.yaml)")
+	rootCmd.PersistentFlags().String("handle", "", "Bluesky handle to authenticate as (env BSKY_HANDLE)")
+	rootCmd.PersistentFlags().String("app-pwd", "", "Bluesky app password (env BSKY_APP_PWD)")
+	rootCmd.PersistentFlags().String("instance", "https://bsky.social", "PDS/instance host to talk to (env BSKY_INSTANCE)")
+	rootCmd.PersistentFlags().Int64("page-size", 100, "number of accounts to request per page")
+	rootCmd.PersistentFlags().String("format", "csv", "output format: csv, json, ndjson, or parquet (parquet requires a -tags parquet build)")
+
+	for _, name := range []string{"handle", "app-pwd", "instance", "page-size", "format"} {
+		if err := viper.BindPFlag(name, rootCmd.PersistentFlags().Lookup(name)); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// initConfig wires up viper to read, in order of precedence, flags, then
+// BSKY_* environment variables, then a .bsky-audit-log.yaml config file
+// in the working directory or home directory.
+func initConfig() {
+	if cfgFile != "" {
+		viper.SetConfigFile(cfgFile)
+	} else {
+		home, err := os.UserHomeDir()
+		cobra.CheckErr(err)
+		viper.AddConfigPath(".")
+		viper.AddConfigPath(home)
+		viper.SetConfigType("yaml")
+		viper.SetConfigName(".bsky-audit-log")
+	}
+
+	viper.SetEnvPrefix("BSKY")
+	viper.AutomaticEnv()
+	// BSKY_APP_PWD would otherwise only bind to "app_pwd"; keep the flag
+	// name and the historical env var in sync explicitly.
+	viper.BindEnv("app-pwd", "BSKY_APP_PWD")
+	viper.BindEnv("handle", "BSKY_HANDLE")
+	viper.BindEnv("instance", "BSKY_INSTANCE")
+
+	if err := viper.ReadInConfig(); err == nil {
+		fmt.Fprintln(os.Stderr, "using config file:", viper.ConfigFileUsed())
+	}
+}
+
+// buildClient authenticates against the configured instance and returns a
+// ready-to-use bsky.Client.
+func buildClient(ctx context.Context) (*bsky.Client, error) {
+	handle := viper.GetString("handle")
+	appPwd := viper.GetString("app-pwd")
+	instance := viper.GetString("instance")
+	if handle == "" || appPwd == "" || instance == "" {
+		return nil, fmt.Errorf("handle, app-pwd, and instance must all be set (via flags, config file, or BSKY_* env vars)")
+	}
+	return bsky.NewClient(ctx, instance, handle, appPwd)
+}
+
+// relationFunc is the shape shared by bsky.Client's Following, Followers,
+// Mutes, and Blocks methods.
+type relationFunc func(ctx context.Context, did string, pageSize int64) ([]*bsky.Profile, error)
+
+// printAccounts runs fn to completion and writes the resulting accounts to
+// stdout in the configured --format.
+func printAccounts(ctx context.Context, did string, fn relationFunc) error {
+	out, err := fn(ctx, did, viper.GetInt64("page-size"))
+	if err != nil {
+		return err
+	}
+
+	enc, err := newEncoder(viper.GetString("format"), os.Stdout)
+	if err != nil {
+		return err
+	}
+	for _, acc := range out {
+		if err := enc.Encode(acc); err != nil {
+			return err
+		}
+	}
+	return enc.Close()
+}
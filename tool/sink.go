@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	_ "modernc.org/sqlite"
+)
+
+// newSink builds the Sink named by kind, creating any files it needs under
+// dataDir. Supported kinds are "stdout", "jsonl", and "sqlite".
+func newSink(kind, dataDir string) (Sink, error) {
+	switch kind {
+	case "", "stdout":
+		return stdoutSink{}, nil
+	case "jsonl":
+		if err := os.MkdirAll(dataDir, 0o755); err != nil {
+			return nil, fmt.Errorf("creating data dir: %w", err)
+		}
+		return newJSONLSink(filepath.Join(dataDir, "audit.jsonl"))
+	case "sqlite":
+		if err := os.MkdirAll(dataDir, 0o755); err != nil {
+			return nil, fmt.Errorf("creating data dir: %w", err)
+		}
+		return newSQLiteSink(filepath.Join(dataDir, "audit.db"))
+	default:
+		return nil, fmt.Errorf("unknown sink kind %q", kind)
+	}
+}
+
+// stdoutSink writes one human-readable line per event to stdout.
+type stdoutSink struct{}
+
+func (stdoutSink) Write(_ context.Context, evt AuditEvent) error {
+	_, err := fmt.Println(evt.String())
+	return err
+}
+
+func (stdoutSink) Close() error { return nil }
+
+// jsonlSink appends one JSON object per line to a file, the on-disk form
+// of the audit log the repo name promises.
+type jsonlSink struct {
+	f *os.File
+	e *json.Encoder
+}
+
+func newJSONLSink(path string) (*jsonlSink, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("opening jsonl sink: %w", err)
+	}
+	return &jsonlSink{f: f, e: json.NewEncoder(f)}, nil
+}
+
+func (s *jsonlSink) Write(_ context.Context, evt AuditEvent) error {
+	return s.e.Encode(evt)
+}
+
+func (s *jsonlSink) Close() error {
+	return s.f.Close()
+}
+
+// sqliteSink persists events to a local SQLite database for ad-hoc
+// querying (e.g. "how many times has X unblocked me this month").
+type sqliteSink struct {
+	db *sql.DB
+}
+
+func newSQLiteSink(path string) (*sqliteSink, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("opening sqlite sink: %w", err)
+	}
+	_, err = db.Exec(`CREATE TABLE IF NOT EXISTS audit_events (
+		time TEXT NOT NULL,
+		action TEXT NOT NULL,
+		did TEXT NOT NULL,
+		handle TEXT NOT NULL
+	)`)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("creating audit_events table: %w", err)
+	}
+	return &sqliteSink{db: db}, nil
+}
+
+func (s *sqliteSink) Write(ctx context.Context, evt AuditEvent) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO audit_events (time, action, did, handle) VALUES (?, ?, ?, ?)`,
+		evt.Time.UTC().Format("2006-01-02T15:04:05Z"), evt.Action, evt.Did, evt.Handle)
+	return err
+}
+
+func (s *sqliteSink) Close() error {
+	return s.db.Close()
+}
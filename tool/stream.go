@@ -0,0 +1,231 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/tehcyx/bsky-audit-log/pkg/bsky"
+)
+
+// defaultJetstreamURL is the public Jetstream instance used when --jetstream-url
+// isn't overridden.
+const defaultJetstreamURL = "wss://jetstream2.us-east.bsky.network/subscribe"
+
+// streamedCollections are the only collections we ask Jetstream for; we
+// don't care about posts, likes, etc. for audit purposes.
+var streamedCollections = []string{
+	"app.bsky.graph.follow",
+	"app.bsky.graph.block",
+	"app.bsky.graph.listitem",
+}
+
+// jetstreamEvent is the subset of Jetstream's event schema this tool reads.
+// See https://github.com/bluesky-social/jetstream for the full schema.
+type jetstreamEvent struct {
+	Did    string           `json:"did"`
+	TimeUS int64            `json:"time_us"`
+	Kind   string           `json:"kind"`
+	Commit *jetstreamCommit `json:"commit,omitempty"`
+}
+
+type jetstreamCommit struct {
+	Rev        string          `json:"rev"`
+	Operation  string          `json:"operation"` // create, update, delete
+	Collection string          `json:"collection"`
+	RKey       string          `json:"rkey"`
+	Record     json.RawMessage `json:"record,omitempty"`
+}
+
+// graphRecord covers app.bsky.graph.follow and app.bsky.graph.block, which
+// both just point at the account being followed/blocked.
+type graphRecord struct {
+	Subject string `json:"subject"`
+}
+
+// recordKey identifies one at-rest record well enough to resolve a later
+// delete back to the subject DID recorded at create time.
+type recordKey struct {
+	repoDid    string
+	collection string
+	rkey       string
+}
+
+// buildJetstreamURL adds the wantedCollections query params Jetstream uses
+// to filter its firehose down to what we care about. We
+// deliberately don't scope this to wantedDids: Jetstream filters wantedDids
+// on the DID that *authored* the commit, not on a follow/block record's
+// subject, so a brand-new follower's DID wouldn't be in that list until
+// after they'd already followed. translateJetstreamEvent does the actual
+// selfDid-relevance filtering client-side instead.
+func buildJetstreamURL(base string) (string, error) {
+	u, err := url.Parse(base)
+	if err != nil {
+		return "", fmt.Errorf("parsing jetstream URL: %w", err)
+	}
+	q := u.Query()
+	for _, col := range streamedCollections {
+		q.Add("wantedCollections", col)
+	}
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// runStream connects to a Jetstream websocket covering every repo's
+// follow/block/listitem commits, and turns the ones involving did into the
+// same AuditEvents the polling daemon produces. It loads the persisted
+// snapshot on startup, or takes and saves one if there is none; the event
+// loop does not read that snapshot. Deletes are resolved only against
+// records this run saw created, so deletes of older records are not
+// reported.
+func runStream(ctx context.Context, c *bsky.Client, did, dataDir, jetstreamURL string, pageSize int64, sink Sink) error {
+	if err := os.MkdirAll(dataDir, 0o755); err != nil {
+		return fmt.Errorf("creating data dir: %w", err)
+	}
+
+	snap, err := loadState(dataDir)
+	if err != nil {
+		return fmt.Errorf("loading prior state: %w", err)
+	}
+	if snap == nil {
+		snap, err = takeSnapshot(ctx, c, did, pageSize)
+		if err != nil {
+			return fmt.Errorf("bootstrap snapshot: %w", err)
+		}
+		if err := saveState(dataDir, snap); err != nil {
+			return fmt.Errorf("saving bootstrap snapshot: %w", err)
+		}
+	}
+
+	target, err := buildJetstreamURL(jetstreamURL)
+	if err != nil {
+		return err
+	}
+
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, target, nil)
+	if err != nil {
+		return fmt.Errorf("dialing jetstream: %w", err)
+	}
+	defer conn.Close()
+
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	seen := make(map[recordKey]string) // create-time rkey -> subject, for resolving deletes
+
+	for {
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			if ctx.Err() != nil {
+				return sink.Close()
+			}
+			return fmt.Errorf("reading jetstream message: %w", err)
+		}
+
+		var evt jetstreamEvent
+		if err := json.Unmarshal(msg, &evt); err != nil || evt.Kind != "commit" || evt.Commit == nil {
+			continue
+		}
+
+		auditEvt, ok := translateJetstreamEvent(did, evt, seen)
+		if !ok {
+			continue
+		}
+
+		recordAuditEvent(auditEvt)
+		if err := sink.Write(ctx, auditEvt); err != nil {
+			return fmt.Errorf("writing audit event: %w", err)
+		}
+	}
+}
+
+// translateJetstreamEvent turns a single commit event into an AuditEvent,
+// if it's one we recognize. selfDid is the authenticated account; seen
+// tracks subjects for records this process has watched get created, so a
+// later delete of the same record can be attributed correctly.
+func translateJetstreamEvent(selfDid string, evt jetstreamEvent, seen map[recordKey]string) (AuditEvent, bool) {
+	commit := evt.Commit
+	now := time.UnixMicro(evt.TimeUS)
+	key := recordKey{repoDid: evt.Did, collection: commit.Collection, rkey: commit.RKey}
+
+	switch commit.Collection {
+	case "app.bsky.graph.follow", "app.bsky.graph.block":
+		// action/verb describe the authenticated account doing the
+		// following/blocking; gainedAction/lostAction describe someone
+		// else doing it to the authenticated account. They must stay
+		// keyed off collection so a block from a follower isn't logged
+		// as a follow.
+		var action, verb, gainedAction, lostAction string
+		switch commit.Collection {
+		case "app.bsky.graph.follow":
+			action, verb = "followed", "unfollowed"
+			gainedAction, lostAction = "gained_follower", "lost_follower"
+		case "app.bsky.graph.block":
+			action, verb = "blocked", "unblocked"
+			gainedAction, lostAction = "blocked_by", "unblocked_by"
+		}
+
+		switch commit.Operation {
+		case "create":
+			var rec graphRecord
+			if err := json.Unmarshal(commit.Record, &rec); err != nil {
+				return AuditEvent{}, false
+			}
+			seen[key] = rec.Subject
+			if evt.Did == selfDid {
+				return AuditEvent{Time: now, Action: action, Did: rec.Subject}, true
+			}
+			if rec.Subject == selfDid {
+				return AuditEvent{Time: now, Action: gainedAction, Did: evt.Did}, true
+			}
+		case "delete":
+			subject, ok := seen[key]
+			delete(seen, key)
+			if !ok {
+				// The record predates this process; the next poll-based
+				// daemon run (or a future snapshot reconciliation) is
+				// what catches this one.
+				return AuditEvent{}, false
+			}
+			if evt.Did == selfDid {
+				return AuditEvent{Time: now, Action: verb, Did: subject}, true
+			}
+			if subject == selfDid {
+				return AuditEvent{Time: now, Action: lostAction, Did: evt.Did}, true
+			}
+		}
+
+	case "app.bsky.graph.listitem":
+		// Mute/block-via-list entries don't carry enough context here to
+		// say whether the list itself is a mute list or a block list, so
+		// we surface them as-is rather than guessing. Only list items that
+		// selfDid authored or that name selfDid as their subject count.
+		switch commit.Operation {
+		case "create":
+			var rec graphRecord
+			if err := json.Unmarshal(commit.Record, &rec); err != nil {
+				return AuditEvent{}, false
+			}
+			if evt.Did != selfDid && rec.Subject != selfDid {
+				return AuditEvent{}, false
+			}
+			seen[key] = rec.Subject
+			return AuditEvent{Time: now, Action: "list_item_added", Did: evt.Did}, true
+		case "delete":
+			if _, ok := seen[key]; !ok {
+				return AuditEvent{}, false
+			}
+			delete(seen, key)
+			return AuditEvent{Time: now, Action: "list_item_removed", Did: evt.Did}, true
+		}
+	}
+
+	return AuditEvent{}, false
+}
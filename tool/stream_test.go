@@ -0,0 +1,129 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// TestTranslateJetstreamEvent covers the collection/operation/actor
+// combinations that b7161cc fixed a regression in: a block event from a
+// follower must show up as blocked_by, not as a follower change.
+func TestTranslateJetstreamEvent(t *testing.T) {
+	const selfDid = "did:plc:self"
+	const otherDid = "did:plc:other"
+
+	followRecord, err := json.Marshal(graphRecord{Subject: otherDid})
+	if err != nil {
+		t.Fatalf("marshaling follow record: %v", err)
+	}
+	selfSubjectRecord, err := json.Marshal(graphRecord{Subject: selfDid})
+	if err != nil {
+		t.Fatalf("marshaling follow record: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		evt        jetstreamEvent
+		seenBefore map[recordKey]string
+		wantOK     bool
+		wantAction string
+		wantDid    string
+	}{
+		{
+			name: "self follows someone else",
+			evt: jetstreamEvent{
+				Did:    selfDid,
+				TimeUS: 1,
+				Kind:   "commit",
+				Commit: &jetstreamCommit{
+					Operation:  "create",
+					Collection: "app.bsky.graph.follow",
+					RKey:       "rkey1",
+					Record:     followRecord,
+				},
+			},
+			wantOK:     true,
+			wantAction: "followed",
+			wantDid:    otherDid,
+		},
+		{
+			name: "someone else follows self",
+			evt: jetstreamEvent{
+				Did:    otherDid,
+				TimeUS: 2,
+				Kind:   "commit",
+				Commit: &jetstreamCommit{
+					Operation:  "create",
+					Collection: "app.bsky.graph.follow",
+					RKey:       "rkey2",
+					Record:     selfSubjectRecord,
+				},
+			},
+			wantOK:     true,
+			wantAction: "gained_follower",
+			wantDid:    otherDid,
+		},
+		{
+			name: "someone else blocks self, not logged as a follower change",
+			evt: jetstreamEvent{
+				Did:    otherDid,
+				TimeUS: 3,
+				Kind:   "commit",
+				Commit: &jetstreamCommit{
+					Operation:  "create",
+					Collection: "app.bsky.graph.block",
+					RKey:       "rkey3",
+					Record:     selfSubjectRecord,
+				},
+			},
+			wantOK:     true,
+			wantAction: "blocked_by",
+			wantDid:    otherDid,
+		},
+		{
+			name: "self unfollows someone else, resolved from a prior create",
+			evt: jetstreamEvent{
+				Did:    selfDid,
+				TimeUS: 4,
+				Kind:   "commit",
+				Commit: &jetstreamCommit{
+					Operation:  "delete",
+					Collection: "app.bsky.graph.follow",
+					RKey:       "rkey4",
+				},
+			},
+			seenBefore: map[recordKey]string{
+				{repoDid: selfDid, collection: "app.bsky.graph.follow", rkey: "rkey4"}: otherDid,
+			},
+			wantOK:     true,
+			wantAction: "unfollowed",
+			wantDid:    otherDid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seen := tt.seenBefore
+			if seen == nil {
+				seen = make(map[recordKey]string)
+			}
+			got, ok := translateJetstreamEvent(selfDid, tt.evt, seen)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if got.Action != tt.wantAction {
+				t.Errorf("Action = %q, want %q", got.Action, tt.wantAction)
+			}
+			if got.Did != tt.wantDid {
+				t.Errorf("Did = %q, want %q", got.Did, tt.wantDid)
+			}
+			if !got.Time.Equal(time.UnixMicro(tt.evt.TimeUS)) {
+				t.Errorf("Time = %v, want %v", got.Time, time.UnixMicro(tt.evt.TimeUS))
+			}
+		})
+	}
+}